@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// isInteractiveTTY reports whether stdin is an interactive terminal rather
+// than a pipe or redirected file. The root command only falls back to the
+// Scanln-driven menu when this is true and no subcommand was given.
+func isInteractiveTTY() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return (stat.Mode() & os.ModeCharDevice) != 0
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "ghosthunter",
+	Short: "Unearth archived URLs from the Wayback Machine and friends",
+	Run: func(cmd *cobra.Command, args []string) {
+		if isInteractiveTTY() {
+			runInteractive(cmd.Context())
+			return
+		}
+		cmd.Help()
+	},
+}
+
+// scanFlags backs the `scan` subcommand.
+var scanFlags struct {
+	domain      string
+	domainsFile string
+	extensions  string
+	workers     int
+	output      string
+	out         string
+	silent      bool
+	stdin       bool
+}
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Discover archived URLs for a domain across every enabled provider",
+	RunE:  runScanCmd,
+}
+
+// snapshotFlags backs the `snapshots` subcommand.
+var snapshotFlags struct {
+	domain        string
+	extensions    string
+	output        string
+	out           string
+	resume        bool
+	templatesDir  string
+	severity      string
+	diff          bool
+	diffThreshold int
+}
+
+var snapshotsCmd = &cobra.Command{
+	Use:   "snapshots",
+	Short: "Fetch Wayback Machine snapshot timestamps for previously discovered URLs",
+	RunE:  runSnapshotsCmd,
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List domains already scanned into the results directory",
+	RunE:  runListCmd,
+}
+
+func init() {
+	scanCmd.Flags().StringVarP(&scanFlags.domain, "domain", "d", "", "domain to search")
+	scanCmd.Flags().StringVar(&scanFlags.domainsFile, "domains-file", "", "file of domains to search, one per line")
+	scanCmd.Flags().StringVar(&scanFlags.extensions, "extensions", "", "comma-separated extensions to keep (overrides config.json)")
+	scanCmd.Flags().IntVar(&scanFlags.workers, "workers", 0, "concurrent workers (overrides config.json)")
+	scanCmd.Flags().StringVar(&scanFlags.output, "output", "jsonl", "output format: text, json, jsonl, or csv")
+	scanCmd.Flags().StringVar(&scanFlags.out, "out", "", "output file (defaults to stdout)")
+	scanCmd.Flags().BoolVar(&scanFlags.silent, "silent", false, "suppress progress messages")
+	scanCmd.Flags().BoolVar(&scanFlags.stdin, "stdin", false, "read domains from stdin, one per line")
+
+	snapshotsCmd.Flags().StringVarP(&snapshotFlags.domain, "domain", "d", "", "domain to fetch snapshots for (required)")
+	snapshotsCmd.Flags().StringVar(&snapshotFlags.extensions, "extensions", "", "comma-separated extensions to read URLs from (required)")
+	snapshotsCmd.Flags().StringVar(&snapshotFlags.output, "output", "jsonl", "output format: text, json, jsonl, or csv")
+	snapshotsCmd.Flags().StringVar(&snapshotFlags.out, "out", "", "output file (defaults to stdout)")
+	snapshotsCmd.Flags().BoolVar(&snapshotFlags.resume, "resume", false, "resume a previously interrupted snapshot scan instead of starting fresh (overrides config.json)")
+	snapshotsCmd.Flags().StringVar(&snapshotFlags.templatesDir, "templates", "", "directory of YAML templates to run against fetched snapshot bodies (overrides config.json)")
+	snapshotsCmd.Flags().StringVar(&snapshotFlags.severity, "severity", "", "restrict loaded templates to this severity (overrides config.json)")
+	snapshotsCmd.Flags().BoolVar(&snapshotFlags.diff, "diff", false, "SimHash-diff snapshot bodies and keep only meaningfully different revisions (overrides config.json)")
+	snapshotsCmd.Flags().IntVar(&snapshotFlags.diffThreshold, "diff-threshold", 0, "max Hamming distance between SimHashes still considered \"unchanged\" (overrides config.json)")
+	snapshotsCmd.MarkFlagRequired("domain")
+	snapshotsCmd.MarkFlagRequired("extensions")
+
+	rootCmd.AddCommand(scanCmd, snapshotsCmd, listCmd)
+}
+
+// execute runs the cobra command tree; it's the sole entry point called from
+// main(). ctx carries SIGINT/SIGTERM cancellation down to every subcommand.
+func execute(ctx context.Context) error {
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// urlRecord is one machine-readable record describing a discovered URL,
+// suitable for jq/grep pipelines.
+type urlRecord struct {
+	Domain    string    `json:"domain"`
+	URL       string    `json:"url"`
+	Ext       string    `json:"ext"`
+	Source    string    `json:"source"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// readDomains collects domains from -d/--domain, a domains file, and/or
+// stdin, in that order.
+func readDomains(domain, domainsFile string, fromStdin bool) ([]string, error) {
+	var domains []string
+
+	if domain != "" {
+		domains = append(domains, domain)
+	}
+
+	if domainsFile != "" {
+		file, err := os.Open(domainsFile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if d := strings.TrimSpace(scanner.Text()); d != "" {
+				domains = append(domains, d)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if fromStdin {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if d := strings.TrimSpace(scanner.Text()); d != "" {
+				domains = append(domains, d)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return domains, nil
+}
+
+// openOutput resolves --out to a stdout/file writer, returning a close func.
+func openOutput(path string) (io.Writer, func(), error) {
+	if path == "" {
+		return os.Stdout, func() {}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// writeURLRecords emits url records to w as a JSON array, JSONL, CSV, or
+// plain newline-separated text (URL only, matching the existing .txt files).
+func writeURLRecords(w io.Writer, records []urlRecord, format string) error {
+	switch format {
+	case "text":
+		for _, r := range records {
+			if _, err := fmt.Fprintln(w, r.URL); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		defer cw.Flush()
+		if err := cw.Write([]string{"domain", "url", "ext", "source", "first_seen", "last_seen"}); err != nil {
+			return err
+		}
+		for _, r := range records {
+			row := []string{r.Domain, r.URL, r.Ext, r.Source, r.FirstSeen.Format(time.RFC3339), r.LastSeen.Format(time.RFC3339)}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	default: // jsonl, one record per line
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// writeSnapshotRecords emits snapshot records to w in the same set of
+// formats as writeURLRecords.
+func writeSnapshotRecords(w io.Writer, records []snapshotListRecord, format string) error {
+	switch format {
+	case "text":
+		for _, r := range records {
+			if _, err := fmt.Fprintln(w, r.SnapshotURL); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		defer cw.Flush()
+		if err := cw.Write([]string{"domain", "url", "extension", "timestamp", "snapshot_url"}); err != nil {
+			return err
+		}
+		for _, r := range records {
+			row := []string{r.Domain, r.URL, r.Extension, r.Timestamp, r.SnapshotURL}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	default: // jsonl
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+var extPattern = regexp.MustCompile(`\.([a-zA-Z0-9]+)(\?.*)?$`)
+
+// extOf returns the file extension (without the leading dot) of a URL, or
+// "" if it has none.
+func extOf(u string) string {
+	if matches := extPattern.FindStringSubmatch(u); len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
+
+// runScanCmd is the RunE for `ghosthunter scan`. It delegates discovery,
+// saving, and optional liveness probing to runGhostHunter - the same
+// pipeline the interactive menu uses - so both entry points stay in sync.
+func runScanCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	config := loadConfig()
+
+	if scanFlags.extensions != "" {
+		config.Extensions = strings.Split(scanFlags.extensions, ",")
+	}
+	if scanFlags.workers > 0 {
+		config.NumWorkers = scanFlags.workers
+	}
+
+	domains, err := readDomains(scanFlags.domain, scanFlags.domainsFile, scanFlags.stdin)
+	if err != nil {
+		return err
+	}
+	if len(domains) == 0 {
+		return fmt.Errorf("no domains supplied: use --domain, --domains-file, or --stdin")
+	}
+
+	out, closeOut, err := openOutput(scanFlags.out)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	source := strings.Join(config.Providers, ",")
+
+	var records []urlRecord
+	for _, domain := range domains {
+		if !scanFlags.silent {
+			color.Cyan("Scanning %s...\n", domain)
+		}
+
+		filteredURLs, err := runGhostHunter(ctx, config, domain, scanFlags.silent)
+		if err != nil {
+			return fmt.Errorf("scanning %s: %w", domain, err)
+		}
+
+		now := time.Now()
+		for _, u := range filteredURLs {
+			records = append(records, urlRecord{
+				Domain:    domain,
+				URL:       u,
+				Ext:       extOf(u),
+				Source:    source,
+				FirstSeen: now,
+				LastSeen:  now,
+			})
+		}
+	}
+
+	return writeURLRecords(out, records, scanFlags.output)
+}
+
+// runSnapshotsCmd is the RunE for `ghosthunter snapshots`. It reads
+// previously discovered URLs from results/<domain>/<domain>.<ext>.txt and
+// delegates to fetchSnapshots - the same resumable, queue-backed pipeline
+// the interactive menu uses, including digest-cache skipping, body
+// downloads, template scanning, and SimHash diffing - and emits a structured
+// record per snapshot in addition to the human-oriented .snapshots.txt file
+// that fetchSnapshots still writes.
+func runSnapshotsCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	config := loadConfig()
+
+	if snapshotFlags.resume {
+		config.Resume = true
+	}
+	if snapshotFlags.templatesDir != "" {
+		config.TemplatesDir = snapshotFlags.templatesDir
+	}
+	if snapshotFlags.severity != "" {
+		config.Severity = snapshotFlags.severity
+	}
+	if snapshotFlags.diff {
+		config.DiffEnabled = true
+	}
+	if snapshotFlags.diffThreshold > 0 {
+		config.DiffThreshold = snapshotFlags.diffThreshold
+	}
+
+	domain := snapshotFlags.domain
+	extensions := strings.Split(snapshotFlags.extensions, ",")
+
+	var urls []string
+	for _, ext := range extensions {
+		fileName := fmt.Sprintf("%s.%s.txt", domain, strings.TrimSpace(ext))
+		content, err := os.ReadFile(filepath.Join("results", domain, fileName))
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", fileName, err)
+		}
+		for _, u := range strings.Split(string(content), "\n") {
+			if u = strings.TrimSpace(u); u != "" {
+				urls = append(urls, u)
+			}
+		}
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("no URLs found for domain %s under extensions %v", domain, extensions)
+	}
+
+	out, closeOut, err := openOutput(snapshotFlags.out)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	records, err := fetchSnapshots(ctx, config, urls, domain)
+	if err != nil {
+		return fmt.Errorf("fetching snapshots for %s: %w", domain, err)
+	}
+
+	return writeSnapshotRecords(out, records, snapshotFlags.output)
+}
+
+// runListCmd is the RunE for `ghosthunter list`; it prints domains already
+// scanned into the results directory, one per line.
+func runListCmd(cmd *cobra.Command, args []string) error {
+	domains, err := listAvailableDomains()
+	if err != nil {
+		return err
+	}
+	for _, d := range domains {
+		fmt.Println(d)
+	}
+	return nil
+}
@@ -10,15 +10,37 @@ import (
 
 // Config holds configuration data loaded from config.json
 type Config struct {
-	Extensions []string `json:"extensions"` // List of file extensions to filter
-	NumWorkers int      `json:"numWorkers"` // Number of concurrent workers
+	Extensions        []string `json:"extensions"`        // List of file extensions to filter
+	NumWorkers        int      `json:"numWorkers"`        // Number of concurrent workers
+	Providers         []string `json:"providers"`         // Enabled URL discovery providers (wayback, commoncrawl, otx, urlscan)
+	RequestsPerSecond int      `json:"requestsPerSecond"` // Cap on outbound Wayback requests/sec; 0 disables limiting
+	InsecureTLS       bool     `json:"insecureTLS"`       // Opt-in: skip TLS certificate verification
+	DownloadBodies    bool     `json:"downloadBodies"`    // Download archived page bodies alongside snapshot listings
+	ExtractRulesFile  string   `json:"extractRulesFile"`  // YAML rules file to scan downloaded bodies for secrets/endpoints; empty disables extraction
+	OTXAPIKey         string   `json:"otxAPIKey"`         // Optional AlienVault OTX API key; raises OTX's rate limit when set
+	URLScanAPIKey     string   `json:"urlscanAPIKey"`     // Optional urlscan.io API key; raises urlscan.io's rate limit when set
+	ProbeEnabled      bool     `json:"probeEnabled"`      // Issue a live GET against every filtered URL after saving results
+	Resume            bool     `json:"resume"`            // Resume the snapshot queue from a previous interrupted run instead of starting fresh
+	TemplatesDir      string   `json:"templatesDir"`      // Directory of YAML templates to run against fetched snapshot bodies; empty disables template scanning
+	Severity          string   `json:"severity"`          // Restrict loaded templates to this severity; empty loads all
+	DiffEnabled       bool     `json:"diffEnabled"`       // Fetch and SimHash-compare snapshot bodies, keeping only meaningfully different revisions
+	DiffThreshold     int      `json:"diffThreshold"`     // Max Hamming distance between SimHashes still considered "unchanged"; defaults to 3
 }
 
+// DefaultProviders is used when config.json doesn't specify any.
+var DefaultProviders = []string{"wayback"}
+
+// reqLimiter throttles outbound Wayback requests according to
+// Config.RequestsPerSecond. It's initialized by loadConfig and is nil
+// (no-op) until then.
+var reqLimiter *rateLimiter
+
 // Constants for hardcoded values
 const (
-	DefaultTimeout     = 120 * time.Second
-	DefaultWorkerDelay = 2 * time.Second
-	DefaultNumWorkers  = 5
+	DefaultTimeout       = 120 * time.Second
+	DefaultWorkerDelay   = 2 * time.Second
+	DefaultNumWorkers    = 5
+	DefaultDiffThreshold = 3
 )
 
 // loadConfig reads and parses the config.json file
@@ -46,6 +68,14 @@ func loadConfig() Config {
 	if config.NumWorkers > runtime.NumCPU()*2 {
 		config.NumWorkers = runtime.NumCPU() * 2 // Reasonable upper limit
 	}
+	if len(config.Providers) == 0 {
+		config.Providers = DefaultProviders
+	}
+	if config.DiffThreshold <= 0 {
+		config.DiffThreshold = DefaultDiffThreshold
+	}
+	reqLimiter = newRateLimiter(config.RequestsPerSecond)
+	configureTLS(config.InsecureTLS)
 
 	return config
 }
\ No newline at end of file
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/bits"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"golang.org/x/sync/errgroup"
+)
+
+// diffEntry is one URL's row in snapshots.diff.jsonl.
+type diffEntry struct {
+	URL            string   `json:"url"`
+	KeptTimestamps []string `json:"kept_timestamps"`
+	DroppedCount   int      `json:"dropped_count"`
+	FirstSeen      string   `json:"first_seen"`
+	LastSeen       string   `json:"last_seen"`
+}
+
+var tokenPattern = regexp.MustCompile(`\w+`)
+
+// simhash64 computes a 64-bit SimHash fingerprint over body's tokens. Each
+// token is hashed with fnv64; every bit position accumulates +1 when the
+// token's hash has that bit set and -1 otherwise, and the final bit is 1
+// wherever the running sum across all tokens is positive.
+func simhash64(body string) uint64 {
+	var weights [64]int
+	for _, token := range tokenPattern.FindAllString(body, -1) {
+		h := fnv.New64()
+		h.Write([]byte(token))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for bit := 0; bit < 64; bit++ {
+		if weights[bit] > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+	return fingerprint
+}
+
+// hammingDistance64 counts the differing bits between a and b.
+func hammingDistance64(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// diffURLs runs diffSnapshots for every url in urlTimestamps concurrently
+// and writes one snapshots.diff.jsonl entry per URL.
+func diffURLs(ctx context.Context, urlTimestamps map[string][]string, domain string, threshold int) error {
+	outputDir := filepath.Join("results", domain)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	diffPath := filepath.Join(outputDir, "snapshots.diff.jsonl")
+	file, err := os.Create(diffPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var mu sync.Mutex
+	enc := json.NewEncoder(file)
+
+	type job struct {
+		url        string
+		timestamps []string
+	}
+	jobChan := make(chan job, DefaultNumWorkers)
+
+	g, gctx := errgroup.WithContext(ctx)
+	worker := func() error {
+		for j := range jobChan {
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+
+			entry, err := diffSnapshots(gctx, j.url, j.timestamps, domain, threshold)
+			if err != nil {
+				color.Red("Failed to diff snapshots for %s: %v\n", j.url, err)
+				continue
+			}
+
+			mu.Lock()
+			encErr := enc.Encode(entry)
+			mu.Unlock()
+			if encErr != nil {
+				return encErr
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i < DefaultNumWorkers; i++ {
+		g.Go(worker)
+	}
+
+	for url, timestamps := range urlTimestamps {
+		select {
+		case jobChan <- job{url: url, timestamps: timestamps}:
+		case <-gctx.Done():
+		}
+	}
+	close(jobChan)
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	color.Green("\nSnapshot diff saved to: %s\n", diffPath)
+	return nil
+}
+
+// diffSnapshots fetches the raw body of every snapshot for url, in
+// timestamp order, keeping only those whose SimHash differs from the last
+// kept snapshot by more than threshold - an exact SHA-256 match is always
+// dropped as a fast path. A unified-diff-style patch file is written
+// between consecutive kept text/HTML revisions.
+func diffSnapshots(ctx context.Context, url string, timestamps []string, domain string, threshold int) (diffEntry, error) {
+	diffDir := filepath.Join("results", domain, "diffs")
+	if err := os.MkdirAll(diffDir, 0755); err != nil {
+		return diffEntry{}, err
+	}
+
+	entry := diffEntry{URL: url}
+	var lastDigest string
+	var lastHash uint64
+	var lastTimestamp, lastBody, lastContentType string
+	haveLast := false
+
+	for _, ts := range timestamps {
+		if err := reqLimiter.wait(ctx); err != nil {
+			return entry, err
+		}
+
+		rawURL := fmt.Sprintf("https://web.archive.org/web/%sid_/%s", ts, url)
+		req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+		if err != nil {
+			continue
+		}
+		resp, err := retryableDo(ctx, httpClient, req)
+		if err != nil {
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		contentType := resp.Header.Get("Content-Type")
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+
+		sum := sha256.Sum256(body)
+		digest := hex.EncodeToString(sum[:])
+		hash := simhash64(string(body))
+
+		if haveLast && (digest == lastDigest || hammingDistance64(hash, lastHash) <= threshold) {
+			entry.DroppedCount++
+			continue
+		}
+
+		if haveLast && strings.Contains(strings.ToLower(lastContentType), "html") && strings.Contains(strings.ToLower(contentType), "html") {
+			if err := writeUnifiedDiff(diffDir, url, lastTimestamp, ts, lastBody, string(body)); err != nil {
+				color.Red("Failed to write diff for %s: %v\n", url, err)
+			}
+		}
+
+		entry.KeptTimestamps = append(entry.KeptTimestamps, ts)
+		if entry.FirstSeen == "" {
+			entry.FirstSeen = ts
+		}
+		entry.LastSeen = ts
+
+		lastDigest, lastHash, lastTimestamp, lastBody, haveLast = digest, hash, ts, string(body), true
+		lastContentType = contentType
+	}
+
+	return entry, nil
+}
+
+// writeUnifiedDiff writes a unified-diff-style patch between fromBody and
+// toBody to diffDir, named after the URL and the timestamp pair it spans.
+func writeUnifiedDiff(diffDir, rawURL, fromTS, toTS, fromBody, toBody string) error {
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMain(fromBody, toBody, false)
+	patches := dmp.PatchMake(fromBody, diffs)
+
+	fileName := fmt.Sprintf("%s_%s_to_%s.diff", sha1Hex(rawURL), fromTS, toTS)
+	return os.WriteFile(filepath.Join(diffDir, fileName), []byte(dmp.PatchToText(patches)), 0644)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DigestEntry records the last-known CDX state of a single archived URL so
+// a rerun can tell whether anything actually changed since the previous
+// scan.
+type DigestEntry struct {
+	Timestamp     string    `json:"timestamp"`
+	Digest        string    `json:"digest"`
+	SnapshotCount int       `json:"snapshotCount"`
+	LastSeen      time.Time `json:"lastSeen"`
+}
+
+// Digest is the persisted per-domain cache of DigestEntry, keyed by URL.
+type Digest struct {
+	path string
+	mu   sync.Mutex
+
+	Entries map[string]DigestEntry `json:"entries"`
+}
+
+// loadDigest reads results/<domain>/digest.json, returning an empty Digest
+// if one doesn't exist yet.
+func loadDigest(domain string) (*Digest, error) {
+	d := &Digest{
+		path:    filepath.Join("results", domain, "digest.json"),
+		Entries: make(map[string]DigestEntry),
+	}
+
+	data, err := os.ReadFile(d.path)
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, err
+	}
+	if d.Entries == nil {
+		d.Entries = make(map[string]DigestEntry)
+	}
+	return d, nil
+}
+
+// unchanged reports whether url's most recent timestamp/digest pair matches
+// what was recorded on a previous run.
+func (d *Digest) unchanged(url, timestamp, digest string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.Entries[url]
+	return ok && entry.Timestamp == timestamp && entry.Digest == digest
+}
+
+// lastTimestamp returns the timestamp recorded for url on a previous run, if
+// any, so a resumed run can tell which CDX entries it already saved.
+func (d *Digest) lastTimestamp(url string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.Entries[url]
+	return entry.Timestamp, ok
+}
+
+// update records the latest known CDX state for url.
+func (d *Digest) update(url, timestamp, digest string, snapshotCount int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.Entries[url] = DigestEntry{
+		Timestamp:     timestamp,
+		Digest:        digest,
+		SnapshotCount: snapshotCount,
+		LastSeen:      time.Now(),
+	}
+}
+
+// save persists the digest to results/<domain>/digest.json.
+func (d *Digest) save() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(d.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(d.path, data, 0644)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxPerHostDownloads caps how many snapshot bodies may be downloaded
+// concurrently from the same origin host.
+const maxPerHostDownloads = 4
+
+// snapshotRef identifies a single archived capture to download.
+type snapshotRef struct {
+	URL       string
+	Timestamp string
+}
+
+// snapshotRecord is one line of the results/<domain>/snapshots.jsonl manifest.
+type snapshotRecord struct {
+	URL       string `json:"url"`
+	Timestamp string `json:"timestamp"`
+	Status    int    `json:"status"`
+	SHA256    string `json:"sha256"`
+	Bytes     int64  `json:"bytes"`
+}
+
+// downloadSnapshots fetches the raw body of every snapshot in refs (using
+// Wayback's id_ raw-capture modifier to avoid toolbar injection) and stores
+// it at results/<domain>/snapshots/<sha1(url)>/<timestamp>.<ext>. Files
+// that already exist with a matching Content-Length are skipped, so a
+// rerun resumes instead of redownloading everything. Concurrency is capped
+// per origin host, and a record is appended to snapshots.jsonl for every
+// file actually saved.
+func downloadSnapshots(ctx context.Context, refs []snapshotRef, domain string) error {
+	if err := os.MkdirAll(filepath.Join("results", domain, "snapshots"), 0755); err != nil {
+		return err
+	}
+
+	manifestPath := filepath.Join("results", domain, "snapshots.jsonl")
+	manifest, err := os.OpenFile(manifestPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer manifest.Close()
+
+	var manifestMu sync.Mutex
+	var hostSemMu sync.Mutex
+	hostSem := make(map[string]chan struct{})
+
+	semFor := func(host string) chan struct{} {
+		hostSemMu.Lock()
+		defer hostSemMu.Unlock()
+		sem, ok := hostSem[host]
+		if !ok {
+			sem = make(chan struct{}, maxPerHostDownloads)
+			hostSem[host] = sem
+		}
+		return sem
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	for _, ref := range refs {
+		ref := ref
+		g.Go(func() error {
+			u, err := url.Parse(ref.URL)
+			if err != nil {
+				return nil // skip malformed URLs rather than aborting the whole batch
+			}
+
+			sem := semFor(u.Host)
+			select {
+			case sem <- struct{}{}:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+			defer func() { <-sem }()
+
+			rec, err := downloadSnapshot(gctx, ref, domain)
+			if err != nil {
+				color.Red("Failed to download snapshot %s@%s: %v\n", ref.URL, ref.Timestamp, err)
+				return nil // per-snapshot failures aren't fatal to the batch
+			}
+			if rec == nil {
+				return nil // already downloaded on a previous run
+			}
+
+			data, err := json.Marshal(rec)
+			if err != nil {
+				return err
+			}
+
+			manifestMu.Lock()
+			_, err = fmt.Fprintln(manifest, string(data))
+			manifestMu.Unlock()
+			return err
+		})
+	}
+
+	return g.Wait()
+}
+
+// downloadSnapshot fetches a single snapshot body and writes it to disk,
+// returning a nil record (not an error) when the file is already present
+// with a matching size.
+func downloadSnapshot(ctx context.Context, ref snapshotRef, domain string) (*snapshotRecord, error) {
+	rawURL := fmt.Sprintf("https://web.archive.org/web/%sid_/%s", ref.Timestamp, ref.URL)
+
+	dir := filepath.Join("results", domain, "snapshots", sha1Hex(ref.URL))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	ext := strings.TrimPrefix(filepath.Ext(ref.URL), ".")
+	if ext == "" {
+		ext = "bin"
+	}
+	destPath := filepath.Join(dir, ref.Timestamp+"."+ext)
+
+	if info, err := os.Stat(destPath); err == nil {
+		if err := reqLimiter.wait(ctx); err != nil {
+			return nil, err
+		}
+		headReq, err := http.NewRequestWithContext(ctx, "HEAD", rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if headResp, err := retryableDo(ctx, httpClient, headReq); err == nil {
+			headResp.Body.Close()
+			if headResp.ContentLength > 0 && info.Size() == headResp.ContentLength {
+				return nil, nil // already downloaded on a previous run
+			}
+		}
+	}
+
+	if err := reqLimiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := retryableDo(ctx, httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if info, err := os.Stat(destPath); err == nil && resp.ContentLength > 0 && info.Size() == resp.ContentLength {
+		return nil, nil
+	}
+
+	file, err := os.Create(destPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	written, err := io.Copy(io.MultiWriter(file, hasher), resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshotRecord{
+		URL:       ref.URL,
+		Timestamp: ref.Timestamp,
+		Status:    resp.StatusCode,
+		SHA256:    hex.EncodeToString(hasher.Sum(nil)),
+		Bytes:     written,
+	}, nil
+}
+
+// sha1Hex returns the hex-encoded SHA-1 digest of s, used to namespace
+// downloaded snapshots by original URL.
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
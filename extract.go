@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+)
+
+// ExtractRule describes a single regex-based pattern to hunt for inside
+// downloaded snapshot bodies, loaded from a YAML rules file.
+type ExtractRule struct {
+	Name     string `yaml:"name"`
+	Regex    string `yaml:"regex"`
+	Severity string `yaml:"severity"`
+	Context  string `yaml:"context"`
+
+	compiled *regexp.Regexp
+}
+
+// Finding is one match emitted to results/<domain>/findings.jsonl.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Match    string `json:"match"`
+	Source   string `json:"source"`
+	Severity string `json:"severity"`
+}
+
+// loadExtractRules reads and compiles every rule in a YAML rules file.
+func loadExtractRules(path string) ([]*ExtractRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*ExtractRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parsing rules file %s: %w", path, err)
+	}
+
+	for _, r := range rules {
+		compiled, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid regex: %w", r.Name, err)
+		}
+		r.compiled = compiled
+	}
+
+	return rules, nil
+}
+
+// extractFindings applies every rule to body, tagging each match with
+// source (the file or URL the body came from).
+func extractFindings(rules []*ExtractRule, body []byte, source string) []Finding {
+	var findings []Finding
+	for _, r := range rules {
+		for _, m := range r.compiled.FindAll(body, -1) {
+			findings = append(findings, Finding{
+				Rule:     r.Name,
+				Match:    string(m),
+				Source:   source,
+				Severity: r.Severity,
+			})
+		}
+	}
+	return findings
+}
+
+// extractFromSnapshots walks downloaded snapshot bodies under
+// results/<domain>/snapshots, applies every rule across a bounded pool of
+// DefaultNumWorkers workers (the same worker-pool pattern as diffURLs and
+// runTemplateScan), and writes hits to results/<domain>/findings.jsonl plus
+// a colored summary table grouped by rule.
+func extractFromSnapshots(ctx context.Context, domain string, rules []*ExtractRule) error {
+	snapshotsDir := filepath.Join("results", domain, "snapshots")
+	findingsPath := filepath.Join("results", domain, "findings.jsonl")
+
+	findingsFile, err := os.Create(findingsPath)
+	if err != nil {
+		return err
+	}
+	defer findingsFile.Close()
+
+	var mu sync.Mutex
+	hits := make(map[string]int)
+
+	pathChan := make(chan string, DefaultNumWorkers)
+
+	g, gctx := errgroup.WithContext(ctx)
+	worker := func() error {
+		for path := range pathChan {
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+
+			body, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+
+			findings := extractFindings(rules, body, path)
+			if len(findings) == 0 {
+				continue
+			}
+
+			mu.Lock()
+			for _, f := range findings {
+				hits[f.Rule]++
+				if data, err := json.Marshal(f); err == nil {
+					fmt.Fprintln(findingsFile, string(data))
+				}
+			}
+			mu.Unlock()
+		}
+		return nil
+	}
+
+	for i := 0; i < DefaultNumWorkers; i++ {
+		g.Go(worker)
+	}
+
+	walkErr := filepath.WalkDir(snapshotsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		select {
+		case pathChan <- path:
+		case <-gctx.Done():
+		}
+		return nil
+	})
+	close(pathChan)
+
+	if gerr := g.Wait(); gerr != nil {
+		return gerr
+	}
+	if walkErr != nil {
+		return walkErr
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Rule", "Severity", "Hits"})
+	table.SetBorder(false)
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiCyanColor},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiCyanColor},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiCyanColor},
+	)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.FgHiGreenColor},
+		tablewriter.Colors{tablewriter.FgHiMagentaColor},
+		tablewriter.Colors{tablewriter.FgHiYellowColor},
+	)
+	for _, r := range rules {
+		table.Append([]string{r.Name, r.Severity, fmt.Sprintf("%d", hits[r.Name])})
+	}
+
+	fmt.Println("\nExtraction Summary:")
+	table.Render()
+	fmt.Printf("\nFindings saved to: %s\n", findingsPath)
+
+	return nil
+}
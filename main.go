@@ -1,17 +1,33 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
 )
 
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := execute(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runInteractive is the Scanln-driven menu used when ghosthunter is invoked
+// with no subcommand on an interactive terminal.
+func runInteractive(ctx context.Context) {
 	displayWelcomeMessage()
 
 	startTime := time.Now()
@@ -26,75 +42,143 @@ func main() {
 		return
 	}
 
-	if err := runGhostHunter(config, domain); err != nil {
+	if _, err := runGhostHunter(ctx, config, domain, false); err != nil {
 		color.Red("Error: %v\n", err)
 		return
 	}
 
-	searchSnapshots()
+	searchSnapshots(ctx, config)
 
 	duration := time.Since(startTime)
 	color.Cyan("\nTOTAL duration: %.2f seconds\n", duration.Seconds())
 }
 
-// runGhostHunter contains the core logic of the program
-func runGhostHunter(config Config, domain string) error {
+// runGhostHunter contains the core logic of the program: it discovers URLs
+// for domain across every enabled provider, filters and saves them, and
+// (when config.ProbeEnabled) probes them for liveness. It returns the
+// filtered URLs so callers that need structured output (e.g. `scan`) don't
+// have to reimplement discovery themselves. Progress messages are suppressed
+// when silent is true.
+func runGhostHunter(ctx context.Context, config Config, domain string, silent bool) ([]string, error) {
+	logGreen := func(format string, a ...interface{}) {
+		if !silent {
+			color.Green(format, a...)
+		}
+	}
+	logCyan := func(format string, a ...interface{}) {
+		if !silent {
+			color.Cyan(format, a...)
+		}
+	}
+	logRed := func(format string, a ...interface{}) {
+		if !silent {
+			color.Red(format, a...)
+		}
+	}
+
 	if !checkInternetConnection() {
-		return fmt.Errorf("no internet or slow connection")
+		return nil, fmt.Errorf("no internet or slow connection")
 	}
-	color.Green("Connected to the Internet!")
+	logGreen("Connected to the Internet!")
 
 	if !checkWaybackMachine() {
-		return fmt.Errorf("Wayback Machine is currently DOWN")
+		return nil, fmt.Errorf("Wayback Machine is currently DOWN")
 	}
-	color.Green("Wayback Machine is UP and running.")
+	logGreen("Wayback Machine is UP and running.")
 
 	if !checkDomainAvailability(domain) {
-		return fmt.Errorf("domain is not reachable")
+		return nil, fmt.Errorf("domain is not reachable")
 	}
-	color.Green("Domain is active!")
+	logGreen("Domain is active!")
 
 	outputDir := filepath.Join("results", domain)
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory '%s': %v", outputDir, err)
+		return nil, fmt.Errorf("failed to create directory '%s': %v", outputDir, err)
 	}
-	color.Green("\nDirectory '%s' created successfully.\n", outputDir)
+	logGreen("\nDirectory '%s' created successfully.\n", outputDir)
 
-	s := spinner.New(spinner.CharSets[36], 100*time.Millisecond)
-	s.Prefix = "\nFetching data from Wayback Machine "
-	s.Start()
-
-	apiURL := "https://web.archive.org/cdx/search/cdx"
-	params := url.Values{}
-	params.Add("url", "*."+domain+"/*")
-	params.Add("collapse", "urlkey")
-	params.Add("output", "text")
-	params.Add("fl", "original")
+	providers := make([]Provider, 0, len(config.Providers))
+	for _, name := range config.Providers {
+		p, err := newProvider(name, config)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
 
-	urlsChan, errChan := fetchURLsConcurrently(apiURL, params)
+	var s *spinner.Spinner
+	if !silent {
+		s = spinner.New(spinner.CharSets[36], 100*time.Millisecond)
+		s.Prefix = fmt.Sprintf("\nFetching data from %d provider(s) ", len(providers))
+		s.Start()
+	}
 
-	// We need to wait for fetching to complete or at least handle errors
-	// But filterURLs consumes the channel, so we can run it directly.
-	// However, we also want to know the total count of found URLs which we can't know until we consume the channel.
-	// filterURLs returns a slice, so it consumes the whole channel.
+	urlsChan, errChan, counts := mergeProviders(ctx, providers, domain)
 
+	// filterURLs drains urlsChan completely, so by the time it returns every
+	// provider goroutine has also finished and closed errChan - it's safe to
+	// range over it here without losing or blocking on errors.
 	filteredURLs := filterURLs(urlsChan, config.Extensions)
 
-	// Check for errors from fetching
-	select {
-	case err := <-errChan:
-		if err != nil {
-			s.Stop()
-			return fmt.Errorf("error fetching URLs: %v", err)
+	var fetchErrs []error
+	failedProviders := make(map[string]struct{})
+	for err := range errChan {
+		fetchErrs = append(fetchErrs, err)
+		failedProviders[strings.SplitN(err.Error(), ":", 2)[0]] = struct{}{}
+	}
+	if s != nil {
+		s.Stop()
+	}
+	for _, err := range fetchErrs {
+		logRed("error fetching URLs: %v\n", err)
+	}
+	if len(fetchErrs) > 0 && len(failedProviders) >= len(providers) {
+		return nil, fmt.Errorf("every provider failed: %v", fetchErrs[0])
+	}
+
+	if !silent {
+		printSourceCounts(providers, counts)
+	}
+	logCyan("\nTotal URLs found (filtered): %d\n", len(filteredURLs))
+
+	if err := saveResultsByExtension(ctx, filteredURLs, domain, outputDir); err != nil {
+		return nil, fmt.Errorf("error saving results: %v", err)
+	}
+
+	if config.ProbeEnabled {
+		logCyan("\nProbing %d URLs for liveness...\n", len(filteredURLs))
+		if err := probeURLs(ctx, config, filteredURLs, domain); err != nil {
+			logRed("Failed to probe URLs: %v\n", err)
 		}
-	default:
 	}
-	s.Stop()
 
-	color.Cyan("\nTotal URLs found (filtered): %d\n", len(filteredURLs))
+	logGreen("Process completed! Results saved in directory '%s'.\n", outputDir)
+	return filteredURLs, nil
+}
 
-	saveResultsByExtension(filteredURLs, domain, outputDir)
+// printSourceCounts renders a table of how many unique URLs each enabled
+// provider contributed after deduplication against the others: a URL
+// returned by several providers counts only for the one that delivered it
+// first.
+func printSourceCounts(providers []Provider, counts *sourceCounts) {
+	snapshot := counts.snapshot()
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Source", "URLs Contributed"})
+	table.SetBorder(false)
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiCyanColor},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiCyanColor},
+	)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.FgHiGreenColor},
+		tablewriter.Colors{tablewriter.FgHiYellowColor},
+	)
+
+	for _, p := range providers {
+		table.Append([]string{p.Name(), fmt.Sprintf("%d", snapshot[p.Name()])})
+	}
 
-	color.Green("Process completed! Results saved in directory '%s'.\n", outputDir)
-	return nil
+	fmt.Println("\nSources Summary:")
+	table.Render()
 }
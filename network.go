@@ -2,10 +2,13 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"crypto/tls"
 	"net/http"
 	"net/url"
 	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // Shared HTTP client with connection pooling
@@ -15,10 +18,19 @@ var httpClient = &http.Client{
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 100,
 		IdleConnTimeout:     90 * time.Second,
-		TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+		TLSClientConfig:     &tls.Config{},
 	},
 }
 
+// configureTLS toggles certificate verification on the shared client
+// according to the insecureTLS config flag. Skipping verification is
+// opt-in only - by default every request verifies certificates normally.
+func configureTLS(insecure bool) {
+	if t, ok := httpClient.Transport.(*http.Transport); ok {
+		t.TLSClientConfig.InsecureSkipVerify = insecure
+	}
+}
+
 // checkInternetConnection verifies if there’s an active internet connection
 func checkInternetConnection() bool {
 	_, err := httpClient.Get("https://www.google.com")
@@ -49,31 +61,52 @@ func checkDomainAvailability(domain string) bool {
 	return resp.StatusCode >= 200 && resp.StatusCode < 400
 }
 
-// fetchURLsConcurrently retrieves URLs from the Wayback Machine API using streaming
-func fetchURLsConcurrently(apiURL string, params url.Values) (<-chan string, <-chan error) {
+// fetchURLsConcurrently retrieves URLs from the Wayback Machine API using
+// streaming. Fetching runs under an errgroup bound to ctx so a SIGINT
+// cancels the in-flight request instead of leaving it to finish unobserved;
+// an error from another provider does not cancel it.
+func fetchURLsConcurrently(ctx context.Context, apiURL string, params url.Values) (<-chan string, <-chan error) {
 	out := make(chan string)
 	errChan := make(chan error, 1)
 
-	go func() {
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
 		defer close(out)
-		defer close(errChan)
 
-		resp, err := httpClient.Get(apiURL + "?" + params.Encode())
+		if err := reqLimiter.wait(gctx); err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(gctx, "GET", apiURL+"?"+params.Encode(), nil)
 		if err != nil {
-			errChan <- err
-			return
+			return err
+		}
+
+		resp, err := retryableDo(gctx, httpClient, req)
+		if err != nil {
+			return err
 		}
 		defer resp.Body.Close()
 
 		scanner := bufio.NewScanner(resp.Body)
 		for scanner.Scan() {
 			line := scanner.Text()
-			if line != "" {
-				out <- line
+			if line == "" {
+				continue
+			}
+			select {
+			case out <- line:
+			case <-gctx.Done():
+				return gctx.Err()
 			}
 		}
 
-		if err := scanner.Err(); err != nil {
+		return scanner.Err()
+	})
+
+	go func() {
+		defer close(errChan)
+		if err := g.Wait(); err != nil {
 			errChan <- err
 		}
 	}()
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxProbeBodyBytes caps how much of a response body is read when probing,
+// so one huge page can't stall a worker or blow up memory.
+const maxProbeBodyBytes = 2 << 20 // 2 MiB
+
+// maxFaviconBytes caps the favicon download used for fingerprinting.
+const maxFaviconBytes = 1 << 20 // 1 MiB
+
+// ProbeResult is one record written to <domain>.probe.jsonl per live-checked
+// URL.
+type ProbeResult struct {
+	URL           string   `json:"url"`
+	FinalURL      string   `json:"final_url,omitempty"`
+	StatusCode    int      `json:"status_code"`
+	ContentLength int64    `json:"content_length"`
+	ContentType   string   `json:"content_type,omitempty"`
+	Server        string   `json:"server,omitempty"`
+	Technologies  []string `json:"technologies,omitempty"`
+	BodySHA256    string   `json:"body_sha256,omitempty"`
+	FaviconHash   *int32   `json:"favicon_hash,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// techFingerprints maps a technology name to a regex matched against the
+// response headers and body. Deliberately coarse - good enough to cluster
+// results, not a full Wappalyzer port.
+var techFingerprints = map[string]*regexp.Regexp{
+	"wordpress": regexp.MustCompile(`wp-content|wp-includes`),
+	"jquery":    regexp.MustCompile(`jquery(?:-|\.)[0-9]`),
+	"react":     regexp.MustCompile(`data-reactroot|react-dom`),
+	"bootstrap": regexp.MustCompile(`bootstrap(?:\.min)?\.(?:css|js)`),
+	"nginx":     regexp.MustCompile(`(?i)nginx`),
+	"apache":    regexp.MustCompile(`(?i)apache`),
+	"php":       regexp.MustCompile(`(?i)x-powered-by:\s*php`),
+}
+
+// probeURLs issues a live GET against every URL through a worker pool,
+// recording liveness and fingerprint data to <domain>.probe.jsonl alongside
+// the existing extension-filtered .txt files, then prints a status-code
+// summary table.
+func probeURLs(ctx context.Context, config Config, urls []string, domain string) error {
+	outputDir := filepath.Join("results", domain)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	outPath := filepath.Join(outputDir, domain+".probe.jsonl")
+	file, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	numWorkers := config.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = DefaultNumWorkers
+	}
+	urlChan := make(chan string, numWorkers)
+
+	var mu sync.Mutex
+	enc := json.NewEncoder(file)
+	statusCounts := make(map[string]int)
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	worker := func() error {
+		for u := range urlChan {
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+			if err := reqLimiter.wait(gctx); err != nil {
+				return err
+			}
+
+			result := probeOne(gctx, u)
+
+			mu.Lock()
+			if err := enc.Encode(result); err != nil {
+				color.Red("Failed to write probe result for %s: %v\n", u, err)
+			}
+			statusCounts[statusBucket(result.StatusCode)]++
+			mu.Unlock()
+		}
+		return nil
+	}
+
+	for i := 0; i < numWorkers; i++ {
+		g.Go(worker)
+	}
+
+	for _, u := range urls {
+		select {
+		case urlChan <- u:
+		case <-gctx.Done():
+		}
+	}
+	close(urlChan)
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	printProbeSummary(statusCounts, outPath)
+	return nil
+}
+
+// probeOne issues a single GET against rawURL and fills in everything a
+// ProbeResult can capture; failures are recorded in Error rather than
+// aborting the scan.
+func probeOne(ctx context.Context, rawURL string) ProbeResult {
+	result := ProbeResult{URL: rawURL}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.ContentType = resp.Header.Get("Content-Type")
+	result.Server = resp.Header.Get("Server")
+	if resp.Request != nil && resp.Request.URL != nil {
+		result.FinalURL = resp.Request.URL.String()
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodyBytes))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ContentLength = int64(len(body))
+
+	sum := sha256.Sum256(body)
+	result.BodySHA256 = hex.EncodeToString(sum[:])
+	result.Technologies = detectTechnologies(resp.Header, body)
+
+	if strings.Contains(strings.ToLower(result.ContentType), "text/html") && result.FinalURL != "" {
+		if hash, err := fetchFaviconHash(ctx, result.FinalURL); err == nil {
+			result.FaviconHash = &hash
+		}
+	}
+
+	return result
+}
+
+// detectTechnologies matches techFingerprints against the Server/X-Powered-By
+// headers and the response body.
+func detectTechnologies(header http.Header, body []byte) []string {
+	haystack := "Server: " + header.Get("Server") + "\nX-Powered-By: " + header.Get("X-Powered-By") + "\n" + string(body)
+
+	var found []string
+	for name, re := range techFingerprints {
+		if re.MatchString(haystack) {
+			found = append(found, name)
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+// fetchFaviconHash downloads /favicon.ico relative to pageURL and returns its
+// mmh3 hash of the base64-encoded bytes - the convention Shodan/httpx use so
+// favicon hashes are directly comparable against those tools' output.
+func fetchFaviconHash(ctx context.Context, pageURL string) (int32, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return 0, err
+	}
+	faviconURL := fmt.Sprintf("%s://%s/favicon.ico", u.Scheme, u.Host)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, faviconURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("favicon not found: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFaviconBytes))
+	if err != nil {
+		return 0, err
+	}
+
+	return mmh3Hash32(mimeEncode(data)), nil
+}
+
+// mimeEncode base64-encodes data with a newline inserted every 76 characters
+// plus a trailing newline, matching Python's base64.encodebytes - the input
+// mmh3 favicon hashing expects.
+func mimeEncode(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var b strings.Builder
+	for i := 0; i < len(encoded); i += 76 {
+		end := i + 76
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		b.WriteString(encoded[i:end])
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// mmh3Hash32 implements the x86 32-bit variant of MurmurHash3 with seed 0.
+func mmh3Hash32(data []byte) int32 {
+	const c1 = 0xcc9e2d51
+	const c2 = 0x1b873593
+
+	var h uint32
+	length := len(data)
+	nblocks := length / 4
+
+	for i := 0; i < nblocks; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4 : i*4+4])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	var k uint32
+	tail := data[nblocks*4:]
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(length)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+
+	return int32(h)
+}
+
+// statusBucket groups an HTTP status code into the bucket shown in the
+// probe summary table.
+func statusBucket(code int) string {
+	switch {
+	case code == 0:
+		return "error"
+	case code < 200:
+		return "1xx"
+	case code < 300:
+		return "2xx"
+	case code < 400:
+		return "3xx"
+	case code < 500:
+		return "4xx"
+	default:
+		return "5xx"
+	}
+}
+
+// printProbeSummary renders the per-status-bucket counts collected during a
+// probe pass.
+func printProbeSummary(statusCounts map[string]int, outPath string) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Status", "Count"})
+	table.SetBorder(false)
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiCyanColor},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiCyanColor},
+	)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.FgHiGreenColor},
+		tablewriter.Colors{tablewriter.FgHiYellowColor},
+	)
+
+	for _, bucket := range []string{"2xx", "3xx", "4xx", "5xx", "1xx", "error"} {
+		if count, ok := statusCounts[bucket]; ok {
+			table.Append([]string{bucket, fmt.Sprintf("%d", count)})
+		}
+	}
+
+	fmt.Println("\nProbe Summary:")
+	table.Render()
+	color.Green("\nProbe results saved to: %s\n", outPath)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
+	"golang.org/x/sync/errgroup"
 )
 
 // filterURLs filters URLs based on specified extensions using a channel
@@ -28,8 +30,10 @@ func filterURLs(input <-chan string, extensions []string) []string {
 	return filteredURLs
 }
 
-// saveResultsByExtension saves filtered URLs to files grouped by extension
-func saveResultsByExtension(urls []string, domain string, outputDir string) {
+// saveResultsByExtension saves filtered URLs to files grouped by extension.
+// Writes run under an errgroup bound to ctx: a SIGINT or a sibling write
+// failure cancels the remaining writes instead of letting them race on.
+func saveResultsByExtension(ctx context.Context, urls []string, domain string, outputDir string) error {
 	extensionMap := make(map[string][]string)
 	re := regexp.MustCompile(`\.([a-zA-Z0-9]+)(\?.*)?$`)
 
@@ -57,14 +61,17 @@ func saveResultsByExtension(urls []string, domain string, outputDir string) {
 		tablewriter.Colors{tablewriter.FgHiMagentaColor},
 	)
 
-	var wg sync.WaitGroup
 	var mu sync.Mutex
 	totalURLs := 0
 
+	g, gctx := errgroup.WithContext(ctx)
 	for ext, urls := range extensionMap {
-		wg.Add(1)
-		go func(ext string, urls []string) {
-			defer wg.Done()
+		ext, urls := ext, urls
+		g.Go(func() error {
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+
 			fileName := fmt.Sprintf("%s.%s.txt", domain, ext)
 			filePath := filepath.Join(outputDir, fileName)
 
@@ -74,7 +81,7 @@ func saveResultsByExtension(urls []string, domain string, outputDir string) {
 				mu.Lock()
 				table.Append([]string{ext, fileName, color.RedString("Failed"), fmt.Sprintf("%d URLs", len(urls))})
 				mu.Unlock()
-				return
+				return err
 			}
 			defer file.Close()
 
@@ -87,18 +94,17 @@ func saveResultsByExtension(urls []string, domain string, outputDir string) {
 				mu.Lock()
 				table.Append([]string{ext, fileName, color.RedString("Failed"), fmt.Sprintf("%d URLs", len(urls))})
 				mu.Unlock()
-			} else {
-				mu.Lock()
-				table.Append([]string{ext, fileName, color.GreenString("Success"), fmt.Sprintf("%d URLs", len(urls))})
-				mu.Unlock()
+				return err
 			}
 
 			mu.Lock()
+			table.Append([]string{ext, fileName, color.GreenString("Success"), fmt.Sprintf("%d URLs", len(urls))})
 			totalURLs += len(urls)
 			mu.Unlock()
-		}(ext, urls)
+			return nil
+		})
 	}
-	wg.Wait()
+	err := g.Wait()
 
 	table.Append([]string{"", "", "", ""})
 	table.Append([]string{"", "", "-------------------", "-------------------"})
@@ -106,4 +112,6 @@ func saveResultsByExtension(urls []string, domain string, outputDir string) {
 
 	fmt.Println("\nResults Summary:")
 	table.Render()
+
+	return err
 }
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Provider discovers historical/archived URLs for a domain from a single data source.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, domain string) (<-chan string, <-chan error)
+}
+
+// newProvider constructs the Provider registered under the given name, or an
+// error if the name isn't recognized. Optional API keys for providers that
+// accept one are read from config.
+func newProvider(name string, config Config) (Provider, error) {
+	switch strings.ToLower(name) {
+	case "wayback":
+		return WaybackProvider{}, nil
+	case "commoncrawl":
+		return CommonCrawlProvider{}, nil
+	case "otx":
+		return OTXProvider{APIKey: config.OTXAPIKey}, nil
+	case "urlscan":
+		return URLScanProvider{APIKey: config.URLScanAPIKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider: %s", name)
+	}
+}
+
+// urlSet is a concurrent-safe set used to deduplicate URLs streamed from
+// multiple providers.
+type urlSet struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+func newURLSet() *urlSet {
+	return &urlSet{seen: make(map[string]struct{})}
+}
+
+// addIfNew reports whether u was not already present, inserting it as a
+// side effect.
+func (s *urlSet) addIfNew(u string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[u]; ok {
+		return false
+	}
+	s.seen[u] = struct{}{}
+	return true
+}
+
+// sourceCounts tracks how many unique URLs each provider contributed, for
+// the per-source summary table printed after a scan.
+type sourceCounts struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newSourceCounts() *sourceCounts {
+	return &sourceCounts{counts: make(map[string]int)}
+}
+
+func (s *sourceCounts) add(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[name]++
+}
+
+// snapshot returns a point-in-time copy safe to range over without holding
+// the lock.
+func (s *sourceCounts) snapshot() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]int, len(s.counts))
+	for k, v := range s.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// mergeProviders fans Fetch out across providers concurrently, deduplicates
+// the combined stream with an urlSet, and returns a single merged channel
+// pair plus a live per-source count. The returned channels are closed once
+// every provider has finished; counts are only complete once errChan is
+// drained to closure.
+func mergeProviders(ctx context.Context, providers []Provider, domain string) (<-chan string, <-chan error, *sourceCounts) {
+	out := make(chan string)
+	errChan := make(chan error, len(providers))
+	seen := newURLSet()
+	counts := newSourceCounts()
+
+	var wg sync.WaitGroup
+	for _, p := range providers {
+		wg.Add(1)
+		go func(p Provider) {
+			defer wg.Done()
+			urls, errs := p.Fetch(ctx, domain)
+			for urls != nil || errs != nil {
+				select {
+				case u, ok := <-urls:
+					if !ok {
+						urls = nil
+						continue
+					}
+					if seen.addIfNew(u) {
+						counts.add(p.Name())
+						out <- u
+					}
+				case err, ok := <-errs:
+					if !ok {
+						errs = nil
+						continue
+					}
+					if err != nil {
+						errChan <- fmt.Errorf("%s: %w", p.Name(), err)
+					}
+				}
+			}
+		}(p)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+		close(errChan)
+	}()
+
+	return out, errChan, counts
+}
+
+// WaybackProvider queries the Wayback Machine CDX API.
+type WaybackProvider struct{}
+
+func (WaybackProvider) Name() string { return "wayback" }
+
+func (WaybackProvider) Fetch(ctx context.Context, domain string) (<-chan string, <-chan error) {
+	apiURL := "https://web.archive.org/cdx/search/cdx"
+	params := url.Values{}
+	params.Add("url", "*."+domain+"/*")
+	params.Add("collapse", "urlkey")
+	params.Add("output", "text")
+	params.Add("fl", "original")
+
+	return fetchURLsConcurrently(ctx, apiURL, params)
+}
+
+// CommonCrawlProvider pulls URLs from the CommonCrawl index, walking every
+// available collection.
+type CommonCrawlProvider struct{}
+
+func (CommonCrawlProvider) Name() string { return "commoncrawl" }
+
+func (CommonCrawlProvider) Fetch(ctx context.Context, domain string) (<-chan string, <-chan error) {
+	out := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errChan)
+
+		collections, err := fetchCommonCrawlCollections(ctx)
+		if err != nil {
+			errChan <- err
+			return
+		}
+
+		for _, collection := range collections {
+			apiURL := fmt.Sprintf("https://index.commoncrawl.org/%s-index", collection)
+			params := url.Values{}
+			params.Add("url", "*."+domain+"/*")
+			params.Add("output", "json")
+
+			if err := reqLimiter.wait(ctx); err != nil {
+				errChan <- err
+				return
+			}
+			req, err := http.NewRequestWithContext(ctx, "GET", apiURL+"?"+params.Encode(), nil)
+			if err != nil {
+				errChan <- err
+				continue
+			}
+			resp, err := retryableDo(ctx, httpClient, req)
+			if err != nil {
+				errChan <- err
+				continue
+			}
+
+			scanner := bufio.NewScanner(resp.Body)
+			scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				line := scanner.Bytes()
+				if len(line) == 0 {
+					continue
+				}
+				var record struct {
+					URL string `json:"url"`
+				}
+				if err := json.Unmarshal(line, &record); err != nil {
+					continue
+				}
+				if record.URL != "" {
+					out <- record.URL
+				}
+			}
+			resp.Body.Close()
+		}
+	}()
+
+	return out, errChan
+}
+
+// fetchCommonCrawlCollections lists the available CommonCrawl index
+// collections (e.g. "CC-MAIN-2024-10").
+func fetchCommonCrawlCollections(ctx context.Context) ([]string, error) {
+	if err := reqLimiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://index.commoncrawl.org/collinfo.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := retryableDo(ctx, httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var collections []struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&collections); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(collections))
+	for _, c := range collections {
+		ids = append(ids, c.ID)
+	}
+	return ids, nil
+}
+
+// OTXProvider queries AlienVault OTX's indicator URL list for a domain. An
+// APIKey is optional but raises OTX's rate limit when supplied.
+type OTXProvider struct {
+	APIKey string
+}
+
+func (OTXProvider) Name() string { return "otx" }
+
+func (p OTXProvider) Fetch(ctx context.Context, domain string) (<-chan string, <-chan error) {
+	out := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errChan)
+
+		page := 1
+		for {
+			if err := reqLimiter.wait(ctx); err != nil {
+				errChan <- err
+				return
+			}
+
+			apiURL := fmt.Sprintf("https://otx.alienvault.com/api/v1/indicators/domain/%s/url_list?page=%d", domain, page)
+			req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			if p.APIKey != "" {
+				req.Header.Set("X-OTX-API-KEY", p.APIKey)
+			}
+			resp, err := retryableDo(ctx, httpClient, req)
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			var result struct {
+				URLList []struct {
+					URL string `json:"url"`
+				} `json:"url_list"`
+				HasNext bool `json:"has_next"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				resp.Body.Close()
+				errChan <- err
+				return
+			}
+			resp.Body.Close()
+
+			for _, entry := range result.URLList {
+				if entry.URL != "" {
+					out <- entry.URL
+				}
+			}
+
+			if !result.HasNext {
+				return
+			}
+			page++
+		}
+	}()
+
+	return out, errChan
+}
+
+// URLScanProvider queries urlscan.io's search API for URLs seen under a
+// domain. An APIKey is optional but raises urlscan.io's rate limit when
+// supplied.
+type URLScanProvider struct {
+	APIKey string
+}
+
+func (URLScanProvider) Name() string { return "urlscan" }
+
+func (p URLScanProvider) Fetch(ctx context.Context, domain string) (<-chan string, <-chan error) {
+	out := make(chan string)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errChan)
+
+		if err := reqLimiter.wait(ctx); err != nil {
+			errChan <- err
+			return
+		}
+
+		apiURL := "https://urlscan.io/api/v1/search/?q=" + url.QueryEscape("domain:"+domain)
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		if p.APIKey != "" {
+			req.Header.Set("API-Key", p.APIKey)
+		}
+		resp, err := retryableDo(ctx, httpClient, req)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		defer resp.Body.Close()
+
+		var result struct {
+			Results []struct {
+				Page struct {
+					URL string `json:"url"`
+				} `json:"page"`
+			} `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			errChan <- err
+			return
+		}
+
+		for _, r := range result.Results {
+			if r.Page.URL != "" {
+				out <- r.Page.URL
+			}
+		}
+	}()
+
+	return out, errChan
+}
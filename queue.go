@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const queueBucketName = "queue"
+
+// pendingBucketName indexes not-yet-claimed URLs by an auto-incrementing
+// sequence key, so claim() can pop the next one in O(1) instead of scanning
+// queueBucketName from the start on every call.
+const pendingBucketName = "pending"
+
+// Queue entry statuses.
+const (
+	statusPending  = "pending"
+	statusInFlight = "in-flight"
+	statusDone     = "done"
+)
+
+// visitQueue is a resumable, disk-backed queue of URLs to visit, backed by a
+// bbolt database at results/<domain>/<name>.queue.db. Claims are committed
+// transactionally, so two workers can never claim the same URL, and unlike a
+// plain in-memory cursor, completion is tracked per URL rather than as a
+// single best-effort low-water mark - a crash mid-scan loses at most the
+// handful of URLs that were in flight.
+type visitQueue struct {
+	db   *bolt.DB
+	path string
+}
+
+// loadVisitQueue opens (or creates) the queue database for domain/name,
+// seeding any URLs not already present. When resume is false, any prior
+// progress for this name is discarded and every URL starts pending again.
+func loadVisitQueue(domain, name string, urls []string, resume bool) (*visitQueue, error) {
+	dir := filepath.Join("results", domain)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, name+".queue.db")
+	db, err := bolt.Open(path, 0644, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &visitQueue{db: db, path: path}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if !resume {
+			if err := tx.DeleteBucket([]byte(queueBucketName)); err != nil && err != bolt.ErrBucketNotFound {
+				return err
+			}
+		}
+		if err := tx.DeleteBucket([]byte(pendingBucketName)); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+
+		bucket, err := tx.CreateBucketIfNotExists([]byte(queueBucketName))
+		if err != nil {
+			return err
+		}
+		pending, err := tx.CreateBucket([]byte(pendingBucketName))
+		if err != nil {
+			return err
+		}
+
+		for _, u := range urls {
+			existing := bucket.Get([]byte(u))
+			needsPending := existing == nil || string(existing) == statusInFlight
+			if string(existing) == statusDone {
+				continue // already completed on a previous run
+			}
+			if needsPending {
+				// New URL, or one left in-flight by a crashed run - (re)queue it.
+				if err := bucket.Put([]byte(u), []byte(statusPending)); err != nil {
+					return err
+				}
+			}
+
+			seq, err := pending.NextSequence()
+			if err != nil {
+				return err
+			}
+			var key [8]byte
+			binary.BigEndian.PutUint64(key[:], seq)
+			if err := pending.Put(key[:], []byte(u)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// claim atomically pops one URL off the pending index, marks it in-flight in
+// queueBucketName, and returns it. ok is false once nothing pending remains.
+// Popping off pendingBucketName's front key is O(1) regardless of how many
+// URLs have already been claimed, unlike scanning queueBucketName from the
+// start on every call.
+func (q *visitQueue) claim() (url string, ok bool, err error) {
+	err = q.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(queueBucketName))
+		pending := tx.Bucket([]byte(pendingBucketName))
+
+		c := pending.Cursor()
+		k, v := c.First()
+		if k == nil {
+			return nil
+		}
+		url = string(v)
+		ok = true
+		if err := c.Delete(); err != nil {
+			return err
+		}
+		return bucket.Put([]byte(url), []byte(statusInFlight))
+	})
+	return url, ok, err
+}
+
+// ack marks url as done.
+func (q *visitQueue) ack(url string) error {
+	return q.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(queueBucketName)).Put([]byte(url), []byte(statusDone))
+	})
+}
+
+// remaining reports how many URLs are still pending or in-flight, so an
+// interrupted scan can tell the operator how much work was left.
+func (q *visitQueue) remaining() (int, error) {
+	var count int
+	err := q.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(queueBucketName)).ForEach(func(_, v []byte) error {
+			if string(v) != statusDone {
+				count++
+			}
+			return nil
+		})
+	})
+	return count, err
+}
+
+// close closes the underlying database file.
+func (q *visitQueue) close() error {
+	return q.db.Close()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// rateLimiter is a token-bucket limiter used to cap outbound Wayback calls
+// at a configurable rate.
+type rateLimiter struct {
+	tokens chan struct{}
+	ticker *time.Ticker
+	done   chan struct{}
+}
+
+// newRateLimiter starts a limiter that refills one token every 1/rps
+// seconds, buffering up to rps tokens. A non-positive rps disables limiting
+// (wait becomes a no-op).
+func newRateLimiter(rps int) *rateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+
+	rl := &rateLimiter{
+		tokens: make(chan struct{}, rps),
+		ticker: time.NewTicker(time.Second / time.Duration(rps)),
+		done:   make(chan struct{}),
+	}
+
+	for i := 0; i < rps; i++ {
+		rl.tokens <- struct{}{}
+	}
+
+	go func() {
+		defer rl.ticker.Stop()
+		for {
+			select {
+			case <-rl.ticker.C:
+				select {
+				case rl.tokens <- struct{}{}:
+				default:
+				}
+			case <-rl.done:
+				return
+			}
+		}
+	}()
+
+	return rl
+}
+
+// wait blocks until a token is available or ctx is canceled. A nil receiver
+// means rate limiting is disabled, so it returns immediately.
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	if rl == nil {
+		return nil
+	}
+	select {
+	case <-rl.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop releases the background refill goroutine.
+func (rl *rateLimiter) stop() {
+	if rl == nil {
+		return
+	}
+	close(rl.done)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	retryBaseDelay   = 1 * time.Second
+	retryFactor      = 2.0
+	retryCapDelay    = 60 * time.Second
+	retryMaxAttempts = 5
+)
+
+// RetryError is returned by retryableDo once retryMaxAttempts have been
+// exhausted without a usable response.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// retryableDo executes req with exponential backoff and jitter (base 1s,
+// factor 2, capped at 60s). It retries on 429/500/502/503/504 responses and
+// on net.Error timeouts, honoring a Retry-After header when the server
+// sends one, and gives up after retryMaxAttempts with a *RetryError.
+func retryableDo(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	var lastErr error
+
+	for attempt := 1; attempt <= retryMaxAttempts; attempt++ {
+		resp, err := client.Do(req.Clone(ctx))
+		if err != nil {
+			lastErr = err
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				return nil, err // non-transient, don't retry
+			}
+		} else if isRetryableStatus(resp.StatusCode) {
+			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+			delay := retryAfterDelay(resp)
+			resp.Body.Close()
+			if delay == 0 {
+				delay = backoffDelay(attempt)
+			}
+			if attempt == retryMaxAttempts {
+				break
+			}
+			if err := sleepCtx(ctx, delay); err != nil {
+				return nil, err
+			}
+			continue
+		} else {
+			return resp, nil
+		}
+
+		if attempt == retryMaxAttempts {
+			break
+		}
+		if err := sleepCtx(ctx, backoffDelay(attempt)); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, &RetryError{Attempts: retryMaxAttempts, Err: lastErr}
+}
+
+// isRetryableStatus reports whether code is worth retrying.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfterDelay parses the Retry-After header as either a number of
+// seconds or an HTTP date, returning 0 if absent or unparsable.
+func retryAfterDelay(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(v); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// backoffDelay returns a jittered exponential delay for the given attempt
+// number, capped at retryCapDelay.
+func backoffDelay(attempt int) time.Duration {
+	d := time.Duration(float64(retryBaseDelay) * math.Pow(retryFactor, float64(attempt-1)))
+	if d > retryCapDelay {
+		d = retryCapDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d/2 + jitter
+}
+
+// sleepCtx sleeps for d unless ctx is canceled first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +16,7 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
+	"golang.org/x/sync/errgroup"
 )
 
 // listAvailableDomains lists domains in the results directory
@@ -119,23 +121,59 @@ func selectExtensions(domain string) ([]string, error) {
 	return selectedExtensions, nil
 }
 
-// fetchSnapshots retrieves and saves snapshots for the given URLs
-func fetchSnapshots(ctx context.Context, urls []string, domain string) {
+// snapshotListRecord is one machine-readable record describing a single
+// timestamped snapshot of a URL, returned by fetchSnapshots for callers
+// (e.g. the `snapshots` CLI subcommand) that need structured output rather
+// than the human-oriented .snapshots.txt file.
+type snapshotListRecord struct {
+	Domain      string `json:"domain"`
+	URL         string `json:"url"`
+	Extension   string `json:"extension"`
+	Timestamp   string `json:"timestamp"`
+	SnapshotURL string `json:"snapshot_url"`
+}
+
+// fetchSnapshots retrieves and saves snapshots for the given URLs, returning
+// a structured record per snapshot found. Reruns load the per-domain digest
+// cache so unchanged CDX entries are skipped, and workers atomically
+// claim/ack URLs from a disk-backed bbolt queue so interrupting mid-scan -
+// and rerunning with config.Resume set - picks up from exactly the URLs left
+// pending or in flight, instead of restarting from URL 0. When
+// config.DownloadBodies is set, the archived page bodies are also
+// downloaded once every URL has been listed.
+func fetchSnapshots(ctx context.Context, config Config, urls []string, domain string) ([]snapshotListRecord, error) {
 	numWorkers := DefaultNumWorkers
-	var wg sync.WaitGroup
-	urlChan := make(chan string, numWorkers)
 
 	outputDir := filepath.Join("results", domain)
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		color.Red("Failed to create directory: %v\n", err)
-		return
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	digest, err := loadDigest(domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load digest cache: %w", err)
+	}
+
+	queue, err := loadVisitQueue(domain, "snapshots", urls, config.Resume)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load visit queue: %w", err)
+	}
+	defer queue.close()
+
+	if remaining, err := queue.remaining(); err == nil && config.Resume && remaining < len(urls) {
+		color.Yellow("Resuming snapshot scan: %d/%d URLs already processed\n", len(urls)-remaining, len(urls))
 	}
 
 	outputFile := filepath.Join(outputDir, domain+".snapshots.txt")
-	file, err := os.Create(outputFile)
+	fileFlags := os.O_CREATE | os.O_WRONLY
+	if config.Resume {
+		fileFlags |= os.O_APPEND
+	} else {
+		fileFlags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(outputFile, fileFlags, 0644)
 	if err != nil {
-		color.Red("Failed to create file: %v\n", err)
-		return
+		return nil, fmt.Errorf("failed to create file: %w", err)
 	}
 	defer file.Close()
 
@@ -152,39 +190,53 @@ func fetchSnapshots(ctx context.Context, urls []string, domain string) {
 	)
 
 	var mu sync.Mutex
+	var snapRefs []snapshotRef
+	var records []snapshotListRecord
+	urlTimestamps := make(map[string][]string)
 
-	worker := func() {
-		defer wg.Done()
-		for url := range urlChan {
-			if url == "" {
-				continue
+	g, gctx := errgroup.WithContext(ctx)
+
+	worker := func() error {
+		for {
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+
+			url, ok, err := queue.claim()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+
+			if err := reqLimiter.wait(gctx); err != nil {
+				return err
 			}
 
-			apiURL := fmt.Sprintf("https://web.archive.org/cdx/search/cdx?url=%s&output=text&fl=timestamp,original", url)
-			req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+			apiURL := fmt.Sprintf("https://web.archive.org/cdx/search/cdx?url=%s&output=text&fl=timestamp,digest,original", url)
+			req, err := http.NewRequestWithContext(gctx, "GET", apiURL, nil)
 			if err != nil {
 				// color.Red("Failed to create request for URL: %s\nError: %v\n", url, err)
 				continue
 			}
-			resp, err := httpClient.Do(req)
+			resp, err := retryableDo(gctx, httpClient, req)
 			if err != nil {
-				// color.Red("Failed to fetch snapshots for URL: %s\nError: %v\n", url, err)
+				var retryErr *RetryError
+				if errors.As(err, &retryErr) {
+					color.Red("Giving up on URL: %s after %d attempts: %v\n", url, retryErr.Attempts, retryErr.Err)
+				}
 				continue
 			}
-			defer resp.Body.Close()
 
-			if resp.StatusCode == http.StatusTooManyRequests {
-				color.Yellow("Rate limit exceeded for URL: %s. Waiting before retrying...\n", url)
-				time.Sleep(10 * time.Second)
-				// Ideally retry, but for now just skip or simple retry logic could be added
-				continue
-			}
 			if resp.StatusCode != http.StatusOK {
+				resp.Body.Close()
 				color.Red("Failed to fetch snapshots for URL: %s\nStatus Code: %d\n", url, resp.StatusCode)
 				continue
 			}
 
 			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
 			if err != nil {
 				color.Red("Failed to read response body for URL: %s\nError: %v\n", url, err)
 				continue
@@ -197,61 +249,154 @@ func fetchSnapshots(ctx context.Context, urls []string, domain string) {
 
 			lines := strings.Split(string(body), "\n")
 			if len(lines) > 1 {
-				color.Cyan("\n────────────────────────────────────────────────────────────────────────")
-				color.Cyan("Snapshots for URL: %s", url)
-				color.Cyan("────────────────────────────────────────────────────────────────────────")
+				lastTimestamp, lastDigest := "", ""
+				if parts := strings.Fields(lines[len(lines)-2]); len(parts) >= 2 {
+					lastTimestamp, lastDigest = parts[0], parts[1]
+				}
 
-				fmt.Fprintf(file, "Snapshots for URL: %s\n", url)
+				if digest.unchanged(url, lastTimestamp, lastDigest) {
+					color.Yellow("\nNo new snapshots since last scan for URL: %s\n", url)
+					mu.Lock()
+					summaryTable.Append([]string{url, "unchanged"})
+					mu.Unlock()
+					queue.ack(url)
+					continue
+				}
+
+				// On a resumed run, domain.snapshots.txt already has every
+				// entry up to prevTimestamp from the previous run appended
+				// to it - only emit the delta so reruns don't duplicate the
+				// full CDX history on every scan.
+				prevTimestamp, hadPrev := digest.lastTimestamp(url)
 
+				type cdxEntry struct {
+					timestamp, originalURL, snapshotURL string
+				}
+				var newEntries []cdxEntry
 				for _, line := range lines {
-					if line != "" {
-						parts := strings.Fields(line)
-						if len(parts) >= 2 {
-							timestamp := parts[0]
-							originalURL := parts[1]
-							snapshotURL := fmt.Sprintf("https://web.archive.org/web/%s/%s", timestamp, originalURL)
-
-							parsedTime, err := time.Parse("20060102150405", timestamp)
-							if err != nil {
-								color.Red("Failed to parse timestamp: %s\nError: %v\n", timestamp, err)
-								continue
-							}
-							formattedTime := parsedTime.Format("02 January 2006, 15:04:05")
-
-							color.Green("  - Timestamp: %s", color.YellowString(formattedTime))
-							color.Green("    URL: %s", color.BlueString(snapshotURL))
-							fmt.Fprintf(file, "  - Timestamp: %s\n    URL: %s\n", formattedTime, snapshotURL)
+					if line == "" {
+						continue
+					}
+					parts := strings.Fields(line)
+					if len(parts) < 3 {
+						continue
+					}
+					timestamp := parts[0]
+					if config.Resume && hadPrev && timestamp <= prevTimestamp {
+						continue // already recorded on a previous run
+					}
+					originalURL := parts[2]
+					newEntries = append(newEntries, cdxEntry{
+						timestamp:   timestamp,
+						originalURL: originalURL,
+						snapshotURL: fmt.Sprintf("https://web.archive.org/web/%s/%s", timestamp, originalURL),
+					})
+				}
+
+				if len(newEntries) > 0 {
+					color.Cyan("\n────────────────────────────────────────────────────────────────────────")
+					color.Cyan("Snapshots for URL: %s", url)
+					color.Cyan("────────────────────────────────────────────────────────────────────────")
+
+					fmt.Fprintf(file, "Snapshots for URL: %s\n", url)
+
+					for _, e := range newEntries {
+						parsedTime, err := time.Parse("20060102150405", e.timestamp)
+						if err != nil {
+							color.Red("Failed to parse timestamp: %s\nError: %v\n", e.timestamp, err)
+							continue
+						}
+						formattedTime := parsedTime.Format("02 January 2006, 15:04:05")
+
+						color.Green("  - Timestamp: %s", color.YellowString(formattedTime))
+						color.Green("    URL: %s", color.BlueString(e.snapshotURL))
+						fmt.Fprintf(file, "  - Timestamp: %s\n    URL: %s\n", formattedTime, e.snapshotURL)
+
+						mu.Lock()
+						records = append(records, snapshotListRecord{
+							Domain:      domain,
+							URL:         e.originalURL,
+							Extension:   extOf(e.originalURL),
+							Timestamp:   e.timestamp,
+							SnapshotURL: e.snapshotURL,
+						})
+						mu.Unlock()
+
+						if config.DownloadBodies || config.TemplatesDir != "" {
+							mu.Lock()
+							snapRefs = append(snapRefs, snapshotRef{URL: e.originalURL, Timestamp: e.timestamp})
+							mu.Unlock()
+						}
+
+						if config.DiffEnabled {
+							mu.Lock()
+							urlTimestamps[e.originalURL] = append(urlTimestamps[e.originalURL], e.timestamp)
+							mu.Unlock()
 						}
 					}
 				}
+				digest.update(url, lastTimestamp, lastDigest, len(lines)-1)
 				mu.Lock()
-				summaryTable.Append([]string{url, fmt.Sprintf("%d snapshots", len(lines)-1)})
+				summaryTable.Append([]string{url, fmt.Sprintf("%d snapshots", len(newEntries))})
 				mu.Unlock()
 			} else {
 				color.Yellow("\nNo snapshots found for URL: %s\n", url)
 				fmt.Fprintf(file, "No snapshots found for URL: %s\n\n", url)
 			}
 
-			// mu.Lock()
-			// processedCount++
-			// fmt.Printf("\rProgress: %d/%d URLs processed", processedCount, totalURLs)
-			// mu.Unlock()
-
-			// time.Sleep(DefaultWorkerDelay) // Rate limiting delay - maybe reduce if using shared client with pooling?
-			// Keep delay to be safe with Wayback Machine
+			queue.ack(url)
 		}
 	}
 
 	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go worker()
+		g.Go(worker)
 	}
 
-	for _, url := range urls {
-		urlChan <- url
+	scanErr := g.Wait()
+	if scanErr != nil {
+		color.Red("\nSnapshot scan stopped: %v\n", scanErr)
+		if remaining, rerr := queue.remaining(); rerr == nil {
+			color.Yellow("%d URL(s) left unprocessed - rerun with \"resume\": true in config.json (or --resume) to pick up where this left off\n", remaining)
+		}
+	}
+	if err := digest.save(); err != nil {
+		color.Red("Failed to save digest cache: %v\n", err)
+	}
+
+	if config.DownloadBodies && len(snapRefs) > 0 {
+		color.Cyan("\nDownloading %d snapshot bodies...\n", len(snapRefs))
+		if err := downloadSnapshots(ctx, snapRefs, domain); err != nil {
+			color.Red("Failed to download snapshot bodies: %v\n", err)
+		} else if config.ExtractRulesFile != "" {
+			rules, err := loadExtractRules(config.ExtractRulesFile)
+			if err != nil {
+				color.Red("Failed to load extraction rules: %v\n", err)
+			} else if err := extractFromSnapshots(ctx, domain, rules); err != nil {
+				color.Red("Failed to extract findings: %v\n", err)
+			}
+		}
+	}
+
+	if config.TemplatesDir != "" && len(snapRefs) > 0 {
+		templates, err := loadTemplates(config.TemplatesDir, config.Severity)
+		if err != nil {
+			color.Red("Failed to load templates: %v\n", err)
+		} else if len(templates) == 0 {
+			color.Yellow("No templates matched severity filter %q in %s\n", config.Severity, config.TemplatesDir)
+		} else {
+			color.Cyan("\nRunning %d template(s) against %d snapshot bodies...\n", len(templates), len(snapRefs))
+			if err := runTemplateScan(ctx, snapRefs, domain, templates); err != nil {
+				color.Red("Template scan failed: %v\n", err)
+			}
+		}
+	}
+
+	if config.DiffEnabled && len(urlTimestamps) > 0 {
+		color.Cyan("\nDiffing snapshots for %d URL(s)...\n", len(urlTimestamps))
+		if err := diffURLs(ctx, urlTimestamps, domain, config.DiffThreshold); err != nil {
+			color.Red("Snapshot diff failed: %v\n", err)
+		}
 	}
-	close(urlChan)
-	wg.Wait()
 	fmt.Println() // Newline after progress bar
 
 	color.Cyan("\n────────────────────────────────────────────────────────────────────────")
@@ -260,10 +405,11 @@ func fetchSnapshots(ctx context.Context, urls []string, domain string) {
 	summaryTable.Render()
 
 	color.Green("\nAll snapshots saved to: %s\n", outputFile)
+	return records, scanErr
 }
 
 // searchSnapshots prompts the user to search for snapshots
-func searchSnapshots() {
+func searchSnapshots(ctx context.Context, config Config) {
 	var choice string
 	color.Cyan("\nDo you want to search for snapshots of the found URLs? (Y/n): ")
 	_, err := fmt.Scanln(&choice)
@@ -305,8 +451,9 @@ func searchSnapshots() {
 	s.Stop()
 
 	startTime := time.Now()
-	ctx := context.Background() // Could be enhanced with timeout
-	fetchSnapshots(ctx, urls, domain)
+	if _, err := fetchSnapshots(ctx, config, urls, domain); err != nil {
+		color.Red("Snapshot fetch error: %v\n", err)
+	}
 
 	duration := time.Since(startTime)
 	fmt.Printf("\nTotal duration for Snapshots Scan: %.2f seconds\n", duration.Seconds())
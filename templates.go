@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+)
+
+// Matcher describes one condition a Template checks against a snapshot.
+type Matcher struct {
+	Type  string   `yaml:"type"`            // "regex" or "word"
+	Part  string   `yaml:"part"`            // "body", "header", or "url"
+	Regex []string `yaml:"regex,omitempty"` // used when Type is "regex"
+	Words []string `yaml:"words,omitempty"` // used when Type is "word"
+
+	compiled []*regexp.Regexp
+}
+
+// Extractor captures a regex match for inclusion in a finding, independent
+// of whether it's one of the matchers that decided the template fired.
+type Extractor struct {
+	Name  string `yaml:"name"`
+	Regex string `yaml:"regex"`
+
+	compiled *regexp.Regexp
+}
+
+// Template is one loaded YAML rule describing what to hunt for inside
+// archived snapshot content.
+type Template struct {
+	ID         string      `yaml:"id"`
+	Severity   string      `yaml:"severity"`
+	Combinator string      `yaml:"combinator"` // "and" or "or"; defaults to "or"
+	Matchers   []Matcher   `yaml:"matchers"`
+	Extractors []Extractor `yaml:"extractors,omitempty"`
+}
+
+// TemplateFinding is one hit appended to results/<domain>/findings.jsonl.
+type TemplateFinding struct {
+	TemplateID  string            `json:"template_id"`
+	Severity    string            `json:"severity"`
+	Match       string            `json:"match"`
+	SnapshotURL string            `json:"snapshot_url"`
+	Timestamp   string            `json:"timestamp"`
+	Extracted   map[string]string `json:"extracted,omitempty"`
+}
+
+// loadTemplates walks dir recursively, parsing and validating every YAML
+// file found. When severity is non-empty, only templates with a matching
+// severity are returned.
+func loadTemplates(dir, severity string) ([]*Template, error) {
+	var templates []*Template
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var t Template
+		if err := yaml.Unmarshal(data, &t); err != nil {
+			return fmt.Errorf("parsing template %s: %w", path, err)
+		}
+		if err := validateTemplate(&t); err != nil {
+			return fmt.Errorf("invalid template %s: %w", path, err)
+		}
+		if severity != "" && !strings.EqualFold(t.Severity, severity) {
+			return nil
+		}
+
+		templates = append(templates, &t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// validateTemplate checks required fields, defaults Combinator, and compiles
+// every regex up front so evaluate/extract never fail at match time.
+func validateTemplate(t *Template) error {
+	if t.ID == "" {
+		return fmt.Errorf("missing id")
+	}
+	if len(t.Matchers) == 0 {
+		return fmt.Errorf("template %q has no matchers", t.ID)
+	}
+	if t.Combinator == "" {
+		t.Combinator = "or"
+	}
+	if t.Combinator != "and" && t.Combinator != "or" {
+		return fmt.Errorf("template %q: combinator must be \"and\" or \"or\"", t.ID)
+	}
+
+	for i := range t.Matchers {
+		m := &t.Matchers[i]
+		switch m.Type {
+		case "regex":
+			for _, pattern := range m.Regex {
+				compiled, err := regexp.Compile(pattern)
+				if err != nil {
+					return fmt.Errorf("template %q: invalid regex %q: %w", t.ID, pattern, err)
+				}
+				m.compiled = append(m.compiled, compiled)
+			}
+		case "word":
+			if len(m.Words) == 0 {
+				return fmt.Errorf("template %q: word matcher has no words", t.ID)
+			}
+		default:
+			return fmt.Errorf("template %q: unknown matcher type %q", t.ID, m.Type)
+		}
+	}
+
+	for i := range t.Extractors {
+		e := &t.Extractors[i]
+		compiled, err := regexp.Compile(e.Regex)
+		if err != nil {
+			return fmt.Errorf("template %q: invalid extractor regex %q: %w", t.ID, e.Regex, err)
+		}
+		e.compiled = compiled
+	}
+
+	return nil
+}
+
+// snapshotContent is the {body, headers, url} triple a Template matches
+// against.
+type snapshotContent struct {
+	body    string
+	headers string
+	url     string
+}
+
+func (c snapshotContent) part(name string) string {
+	switch name {
+	case "header":
+		return c.headers
+	case "url":
+		return c.url
+	default:
+		return c.body
+	}
+}
+
+// matches reports whether m fires against content, and the first string it
+// matched.
+func (m *Matcher) matches(content snapshotContent) (string, bool) {
+	haystack := content.part(m.Part)
+
+	switch m.Type {
+	case "regex":
+		for _, re := range m.compiled {
+			if match := re.FindString(haystack); match != "" {
+				return match, true
+			}
+		}
+	case "word":
+		for _, w := range m.Words {
+			if strings.Contains(haystack, w) {
+				return w, true
+			}
+		}
+	}
+	return "", false
+}
+
+// evaluate applies every matcher in t according to its AND/OR combinator,
+// returning the strings that matched, or nil if t didn't fire.
+func (t *Template) evaluate(content snapshotContent) []string {
+	var hits []string
+	for i := range t.Matchers {
+		match, ok := t.Matchers[i].matches(content)
+		switch {
+		case ok && t.Combinator == "or":
+			return []string{match}
+		case ok:
+			hits = append(hits, match)
+		case t.Combinator == "and":
+			return nil
+		}
+	}
+	return hits
+}
+
+// extract runs every Extractor against content's body, returning named
+// capture values.
+func (t *Template) extract(content snapshotContent) map[string]string {
+	if len(t.Extractors) == 0 {
+		return nil
+	}
+
+	extracted := make(map[string]string)
+	for _, e := range t.Extractors {
+		if match := e.compiled.FindString(content.body); match != "" {
+			extracted[e.Name] = match
+		}
+	}
+	if len(extracted) == 0 {
+		return nil
+	}
+	return extracted
+}
+
+// runTemplateScan fetches the raw body of every snapshot in refs (via
+// web.archive.org's id_ raw-capture modifier) through a rate-limited pool
+// and evaluates every template against it, appending hits to
+// results/<domain>/findings.jsonl.
+func runTemplateScan(ctx context.Context, refs []snapshotRef, domain string, templates []*Template) error {
+	outputDir := filepath.Join("results", domain)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	findingsPath := filepath.Join(outputDir, "findings.jsonl")
+	file, err := os.OpenFile(findingsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var mu sync.Mutex
+	enc := json.NewEncoder(file)
+
+	refChan := make(chan snapshotRef, DefaultNumWorkers)
+	g, gctx := errgroup.WithContext(ctx)
+
+	worker := func() error {
+		for ref := range refChan {
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+			if err := reqLimiter.wait(gctx); err != nil {
+				return err
+			}
+
+			rawURL := fmt.Sprintf("https://web.archive.org/web/%sid_/%s", ref.Timestamp, ref.URL)
+			req, err := http.NewRequestWithContext(gctx, "GET", rawURL, nil)
+			if err != nil {
+				continue
+			}
+			resp, err := retryableDo(gctx, httpClient, req)
+			if err != nil {
+				continue
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				continue
+			}
+
+			content := snapshotContent{
+				body:    string(body),
+				headers: headersToString(resp.Header),
+				url:     ref.URL,
+			}
+
+			for _, t := range templates {
+				hits := t.evaluate(content)
+				if len(hits) == 0 {
+					continue
+				}
+
+				finding := TemplateFinding{
+					TemplateID:  t.ID,
+					Severity:    t.Severity,
+					Match:       hits[0],
+					SnapshotURL: rawURL,
+					Timestamp:   ref.Timestamp,
+					Extracted:   t.extract(content),
+				}
+
+				mu.Lock()
+				if err := enc.Encode(finding); err != nil {
+					mu.Unlock()
+					return err
+				}
+				mu.Unlock()
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i < DefaultNumWorkers; i++ {
+		g.Go(worker)
+	}
+
+	for _, ref := range refs {
+		select {
+		case refChan <- ref:
+		case <-gctx.Done():
+		}
+	}
+	close(refChan)
+
+	return g.Wait()
+}
+
+// headersToString flattens an http.Header into "Key: value\n" lines so a
+// Matcher with part: header can regex/word-match over it like a raw
+// response.
+func headersToString(h http.Header) string {
+	var b strings.Builder
+	for k, vs := range h {
+		for _, v := range vs {
+			b.WriteString(k)
+			b.WriteString(": ")
+			b.WriteString(v)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}